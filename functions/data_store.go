@@ -1,6 +1,7 @@
 package functions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -13,6 +14,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/models"
+	"github.com/m-mizutani/deepalert/internal/service"
 )
 
 /*
@@ -32,31 +36,62 @@ import (
 	- AttrHash: Hashed value of an attribute, generated by all fields of Attribute.
 
 	Primary/secondary key design (in "pk", "sk" field and stored data)
-	- alertmap/{AlertID}, fixedkey -> ReportID
-	- alert/{ReportID}, cache/{random} -> Alert(s)
-	- content/{ReportID}, {AttrHash}/{Random} -> Content(S)
-	- attribute/{ReportID}, {AttrHash} -> Attribute (for caching)
+	- tenant/{TenantID}/alertmap/{AlertID}, fixedkey -> ReportID
+	- tenant/{TenantID}/alert/{ReportID}, cache/{random} -> Alert(s)
+	- tenant/{TenantID}/content/{ReportID}, {AttrHash}/{Random} -> Content(S)
+	- tenant/{TenantID}/attribute/{ReportID}, {AttrHash} -> Attribute (for caching)
 */
 
+// defaultTenantID is used when DataStoreService has no TenantResolver
+// configured, keeping single-tenant deployments working unchanged.
+const defaultTenantID = models.TenantID("default")
+
 type DataStoreService struct {
 	tableName  string
 	region     string
 	table      dynamo.Table
 	timeToLive time.Duration
+	resolver   adaptor.TenantResolver
 }
 
-func NewDataStoreService(tableName, region string) *DataStoreService {
+// NewDataStoreService is constructor of DataStoreService. resolver may be
+// nil, in which case every alert is assigned defaultTenantID.
+func NewDataStoreService(tableName, region string, resolver adaptor.TenantResolver) *DataStoreService {
 	db := dynamo.New(session.New(), &aws.Config{Region: aws.String(region)})
 	x := DataStoreService{
 		tableName:  tableName,
 		region:     region,
 		table:      db.Table(tableName),
 		timeToLive: time.Hour * 3,
+		resolver:   resolver,
 	}
 
 	return &x
 }
 
+// resolveTenant maps alert to a TenantID via the configured TenantResolver,
+// falling back to defaultTenantID when none is configured.
+func (x *DataStoreService) resolveTenant(alert deepalert.Alert) (models.TenantID, error) {
+	if x.resolver == nil {
+		return defaultTenantID, nil
+	}
+
+	tenantID, err := x.resolver.Resolve(alert)
+	if err != nil {
+		return "", errors.Wrap(err, "Fail to resolve tenant for alert")
+	}
+
+	return tenantID, nil
+}
+
+// tenantKey prefixes pk with tenantID so that every record kind below
+// partitions by tenant, mirroring the scheme used by the mock
+// adaptor.Repository (the SQL implementation keeps tenant_id in its own
+// column instead).
+func tenantKey(tenantID models.TenantID, pk string) string {
+	return fmt.Sprintf("tenant/%s/%s", tenantID, pk)
+}
+
 type recordBase struct {
 	PKey      string    `dynamo:"pk"`
 	SKey      string    `dynamo:"sk"`
@@ -83,7 +118,20 @@ func NewReportID() deepalert.ReportID {
 	return deepalert.ReportID(uuid.New().String())
 }
 
-func (x *DataStoreService) TakeReport(alert deepalert.Alert) (*deepalert.Report, error) {
+// TakeReport resolves alert's tenant via the configured TenantResolver and
+// either assigns it a new ReportID or returns the one already cached for
+// its AlertID. The returned TenantID must be threaded through every
+// subsequent Save*/Fetch* call for this report so one tenant's alerts,
+// caches and sections never become visible to another tenant.
+func (x *DataStoreService) TakeReport(ctx context.Context, alert deepalert.Alert) (*deepalert.Report, models.TenantID, error) {
+	ctx, cancel := service.WithDefaultSafetyMargin(ctx)
+	defer cancel()
+
+	tenantID, err := x.resolveTenant(alert)
+	if err != nil {
+		return nil, "", err
+	}
+
 	fixedKey := "Fixed"
 	alertID := alert.AlertID()
 	ts := alert.Timestamp
@@ -91,7 +139,7 @@ func (x *DataStoreService) TakeReport(alert deepalert.Alert) (*deepalert.Report,
 
 	cache := alertEntry{
 		recordBase: recordBase{
-			PKey:      "alertmap/" + alertID,
+			PKey:      tenantKey(tenantID, "alertmap/"+alertID),
 			SKey:      fixedKey,
 			ExpiresAt: ts.Add(time.Hour * 3),
 			CreatedAt: now,
@@ -99,28 +147,35 @@ func (x *DataStoreService) TakeReport(alert deepalert.Alert) (*deepalert.Report,
 		ReportID: NewReportID(),
 	}
 
-	if err := x.table.Put(cache).If("(attribute_not_exists(pk) AND attribute_not_exists(sk)) OR expires_at < ?", ts).Run(); err != nil {
+	if err := x.table.Put(cache).If("(attribute_not_exists(pk) AND attribute_not_exists(sk)) OR expires_at < ?", ts).RunWithContext(ctx); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			// Distinguish a Lambda running out of budget from a genuine
+			// conditional-check failure so callers don't mistake a
+			// timed-out write for a duplicate alert.
+			return nil, "", errors.Wrapf(ctx.Err(), "Deadline exceeded while taking report, AlertID=%s", alertID)
+		}
+
 		if isConditionalCheckErr(err) {
 			var existedEntry alertEntry
-			if err := x.table.Get("pk", cache.PKey).Range("sk", dynamo.Equal, cache.SKey).One(&existedEntry); err != nil {
-				return nil, errors.Wrapf(err, "Fail to get cached reportID, AlertID=%s", alertID)
+			if err := x.table.Get("pk", cache.PKey).Range("sk", dynamo.Equal, cache.SKey).OneWithContext(ctx, &existedEntry); err != nil {
+				return nil, "", errors.Wrapf(err, "Fail to get cached reportID, AlertID=%s", alertID)
 			}
 
 			return &deepalert.Report{
 				ID:        existedEntry.ReportID,
 				Status:    deepalert.StatusMore,
 				CreatedAt: existedEntry.CreatedAt,
-			}, nil
+			}, tenantID, nil
 		}
 
-		return nil, errors.Wrapf(err, "Fail to get cached reportID, AlertID=%s", alertID)
+		return nil, "", errors.Wrapf(err, "Fail to get cached reportID, AlertID=%s", alertID)
 	}
 
 	return &deepalert.Report{
 		ID:        cache.ReportID,
 		Status:    deepalert.StatusNew,
 		CreatedAt: now,
-	}, nil
+	}, tenantID, nil
 }
 
 // -----------------------------------------------------------
@@ -133,17 +188,17 @@ type alertCache struct {
 	ExpiresAt time.Time `dynamo:"expires_at"`
 }
 
-func toAlertCacheKey(reportID deepalert.ReportID) (string, string) {
-	return fmt.Sprintf("alert/%s", reportID), "cache/" + uuid.New().String()
+func toAlertCacheKey(tenantID models.TenantID, reportID deepalert.ReportID) (string, string) {
+	return tenantKey(tenantID, fmt.Sprintf("alert/%s", reportID)), "cache/" + uuid.New().String()
 }
 
-func (x *DataStoreService) SaveAlertCache(reportID deepalert.ReportID, alert deepalert.Alert) error {
+func (x *DataStoreService) SaveAlertCache(ctx context.Context, tenantID models.TenantID, reportID deepalert.ReportID, alert deepalert.Alert) error {
 	raw, err := json.Marshal(alert)
 	if err != nil {
 		return errors.Wrapf(err, "Fail to marshal alert: %v", alert)
 	}
 
-	pk, sk := toAlertCacheKey(reportID)
+	pk, sk := toAlertCacheKey(tenantID, reportID)
 	cache := alertCache{
 		PKey:      pk,
 		SKey:      sk,
@@ -151,19 +206,19 @@ func (x *DataStoreService) SaveAlertCache(reportID deepalert.ReportID, alert dee
 		ExpiresAt: alert.Timestamp.Add(x.timeToLive),
 	}
 
-	if err := x.table.Put(cache).Run(); err != nil {
+	if err := x.table.Put(cache).RunWithContext(ctx); err != nil {
 		return errors.Wrap(err, "")
 	}
 
 	return nil
 }
 
-func (x *DataStoreService) FetchAlertCache(reportID deepalert.ReportID) ([]deepalert.Alert, error) {
-	pk, _ := toAlertCacheKey(reportID)
+func (x *DataStoreService) FetchAlertCache(ctx context.Context, tenantID models.TenantID, reportID deepalert.ReportID) ([]deepalert.Alert, error) {
+	pk, _ := toAlertCacheKey(tenantID, reportID)
 	var caches []alertCache
 	var alerts []deepalert.Alert
 
-	if err := x.table.Get("pk", pk).All(&caches); err != nil {
+	if err := x.table.Get("pk", pk).AllWithContext(ctx, &caches); err != nil {
 		return nil, errors.Wrapf(err, "Fail to retrieve alertCache: %s", reportID)
 	}
 
@@ -186,8 +241,8 @@ type reportSectionRecord struct {
 	Data []byte `dynamo:"data"`
 }
 
-func toReportSectionRecord(reportID deepalert.ReportID, section *deepalert.ReportSection) (string, string) {
-	pk := fmt.Sprintf("content/%s", reportID)
+func toReportSectionRecord(tenantID models.TenantID, reportID deepalert.ReportID, section *deepalert.ReportSection) (string, string) {
+	pk := tenantKey(tenantID, fmt.Sprintf("content/%s", reportID))
 	sk := ""
 	if section != nil {
 		sk = fmt.Sprintf("%s/%s", section.Attribute.Hash(), uuid.New().String())
@@ -195,13 +250,13 @@ func toReportSectionRecord(reportID deepalert.ReportID, section *deepalert.Repor
 	return pk, sk
 }
 
-func (x *DataStoreService) SaveReportSection(section deepalert.ReportSection) error {
+func (x *DataStoreService) SaveReportSection(ctx context.Context, tenantID models.TenantID, section deepalert.ReportSection) error {
 	raw, err := json.Marshal(section)
 	if err != nil {
 		return errors.Wrapf(err, "Fail to marshal ReportSection: %v", section)
 	}
 
-	pk, sk := toReportSectionRecord(section.ReportID, &section)
+	pk, sk := toReportSectionRecord(tenantID, section.ReportID, &section)
 	record := reportSectionRecord{
 		recordBase: recordBase{
 			PKey:      pk,
@@ -211,18 +266,18 @@ func (x *DataStoreService) SaveReportSection(section deepalert.ReportSection) er
 		Data: raw,
 	}
 
-	if err := x.table.Put(record).Run(); err != nil {
+	if err := x.table.Put(record).RunWithContext(ctx); err != nil {
 		return errors.Wrap(err, "Fail to put report record")
 	}
 
 	return nil
 }
 
-func (x *DataStoreService) FetchReportSection(reportID deepalert.ReportID) ([]deepalert.ReportSection, error) {
+func (x *DataStoreService) FetchReportSection(ctx context.Context, tenantID models.TenantID, reportID deepalert.ReportID) ([]deepalert.ReportSection, error) {
 	var records []reportSectionRecord
-	pk, _ := toReportSectionRecord(reportID, nil)
+	pk, _ := toReportSectionRecord(tenantID, reportID, nil)
 
-	if err := x.table.Get("pk", pk).All(&records); err != nil {
+	if err := x.table.Get("pk", pk).AllWithContext(ctx, &records); err != nil {
 		return nil, errors.Wrap(err, "Fail to fetch report records")
 	}
 
@@ -252,7 +307,7 @@ type attributeCache struct {
 
 // PutAttributeCache puts attributeCache to DB and returns true. If the attribute alrady exists,
 // it returns false.
-func (x *DataStoreService) PutAttributeCache(reportID deepalert.ReportID, attr deepalert.Attribute) (bool, error) {
+func (x *DataStoreService) PutAttributeCache(ctx context.Context, tenantID models.TenantID, reportID deepalert.ReportID, attr deepalert.Attribute) (bool, error) {
 	now := time.Now().UTC()
 	var ts time.Time
 	if attr.Timestamp != nil {
@@ -263,7 +318,7 @@ func (x *DataStoreService) PutAttributeCache(reportID deepalert.ReportID, attr d
 
 	cache := attributeCache{
 		recordBase: recordBase{
-			PKey:      "attribute/" + string(reportID),
+			PKey:      tenantKey(tenantID, "attribute/"+string(reportID)),
 			SKey:      attr.Hash(),
 			ExpiresAt: now.Add(time.Hour * 3),
 		},
@@ -273,7 +328,11 @@ func (x *DataStoreService) PutAttributeCache(reportID deepalert.ReportID, attr d
 		AttrValue: attr.Value,
 	}
 
-	if err := x.table.Put(cache).If("(attribute_not_exists(pk) AND attribute_not_exists(sk)) OR expires_at < ?", now).Run(); err != nil {
+	if err := x.table.Put(cache).If("(attribute_not_exists(pk) AND attribute_not_exists(sk)) OR expires_at < ?", now).RunWithContext(ctx); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, errors.Wrapf(ctx.Err(), "Deadline exceeded while putting attr cache reportID=%s", reportID)
+		}
+
 		if isConditionalCheckErr(err) {
 			// The attribute already exists
 			return false, nil
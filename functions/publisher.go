@@ -0,0 +1,69 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/notifier"
+	"github.com/m-mizutani/deepalert/internal/service"
+)
+
+// PublisherService delivers a finalized deepalert.Report to the configured
+// notifiers once the report's sections have all been collected.
+type PublisherService struct {
+	dispatcher *notifier.Dispatcher
+}
+
+// NewPublisherService builds a PublisherService from a declarative notifier
+// config list, typically read from env (DEEPALERT_NOTIFIERS) or SSM.
+func NewPublisherService(configs []adaptor.NotifierConfig, pluginDir string) (*PublisherService, error) {
+	dispatcher, err := notifier.NewDispatcher(configs, pluginDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to build notifier dispatcher")
+	}
+
+	return &PublisherService{dispatcher: dispatcher}, nil
+}
+
+// NotifierConfigsFromEnv parses the DEEPALERT_NOTIFIERS environment
+// variable, a JSON array of adaptor.NotifierConfig, as set by the
+// deployment tooling.
+func NotifierConfigsFromEnv() ([]adaptor.NotifierConfig, error) {
+	raw := os.Getenv("DEEPALERT_NOTIFIERS")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var configs []adaptor.NotifierConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, errors.Wrap(err, "Fail to parse DEEPALERT_NOTIFIERS")
+	}
+
+	return configs, nil
+}
+
+// Publish dispatches report and its sections to every notifier whose
+// filter matches. ctx bounds the outbound notifier calls so a hung
+// endpoint can't block the Publisher Lambda past its execution budget.
+func (x *PublisherService) Publish(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	ctx, cancel := service.WithDefaultSafetyMargin(ctx)
+	defer cancel()
+
+	if err := x.dispatcher.Dispatch(ctx, report, sections); err != nil {
+		return errors.Wrapf(err, "Fail to dispatch notifications for report %s", report.ID)
+	}
+
+	return nil
+}
+
+// Close stops any out-of-tree notifier plugin subprocesses started for
+// this PublisherService. Callers should defer it after NewPublisherService
+// succeeds.
+func (x *PublisherService) Close() {
+	x.dispatcher.Close()
+}
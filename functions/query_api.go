@@ -0,0 +1,93 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/models"
+	"github.com/m-mizutani/deepalert/internal/service"
+)
+
+// QueryAPI answers enumeration/search questions over published reports —
+// e.g. "which reports touched IP X in the last 24h" — by delegating to
+// the configured adaptor.Repository's QueryReports/QueryAttributeUsage.
+type QueryAPI struct {
+	repo adaptor.Repository
+}
+
+// NewQueryAPI is constructor of QueryAPI
+func NewQueryAPI(repo adaptor.Repository) *QueryAPI {
+	return &QueryAPI{repo: repo}
+}
+
+// ReportListRequest is the decoded body/query of a "list reports" call.
+type ReportListRequest struct {
+	TenantID models.TenantID     `json:"tenant_id"`
+	Filter   models.ReportFilter `json:"filter"`
+	Page     models.PageToken    `json:"page"`
+}
+
+// ReportListResponse is the paginated result of ListReports.
+type ReportListResponse struct {
+	Reports  []models.ReportSummary `json:"reports"`
+	NextPage models.PageToken       `json:"next_page,omitempty"`
+}
+
+// ListReports returns a page of report summaries matching req.Filter.
+func (x *QueryAPI) ListReports(ctx context.Context, req ReportListRequest) (*ReportListResponse, error) {
+	reports, next, err := x.repo.QueryReports(ctx, req.TenantID, req.Filter, req.Page)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to query reports")
+	}
+
+	return &ReportListResponse{Reports: reports, NextPage: next}, nil
+}
+
+// AttributeUsageResponse is the result of FindReportsByAttribute.
+type AttributeUsageResponse struct {
+	ReportIDs []deepalert.ReportID `json:"report_ids"`
+}
+
+// FindReportsByAttribute returns every report that touched attrHash.
+func (x *QueryAPI) FindReportsByAttribute(ctx context.Context, tenantID models.TenantID, attrHash string) (*AttributeUsageResponse, error) {
+	ids, err := x.repo.QueryAttributeUsage(ctx, tenantID, attrHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to query attribute usage")
+	}
+
+	return &AttributeUsageResponse{ReportIDs: ids}, nil
+}
+
+// Handler is the API Gateway/Lambda entry point for QueryAPI. action
+// selects between "list_reports" and "find_by_attribute"; raw carries the
+// corresponding JSON request body.
+func (x *QueryAPI) Handler(ctx context.Context, action string, raw []byte) (interface{}, error) {
+	ctx, cancel := service.WithDefaultSafetyMargin(ctx)
+	defer cancel()
+
+	switch action {
+	case "list_reports":
+		var req ReportListRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, errors.Wrap(err, "Fail to parse list_reports request")
+		}
+		return x.ListReports(ctx, req)
+
+	case "find_by_attribute":
+		var req struct {
+			TenantID models.TenantID `json:"tenant_id"`
+			AttrHash string          `json:"attr_hash"`
+		}
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, errors.Wrap(err, "Fail to parse find_by_attribute request")
+		}
+		return x.FindReportsByAttribute(ctx, req.TenantID, req.AttrHash)
+
+	default:
+		return nil, errors.Errorf("Unknown QueryAPI action: %s", action)
+	}
+}
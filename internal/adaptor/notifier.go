@@ -0,0 +1,55 @@
+package adaptor
+
+import (
+	"context"
+
+	"github.com/m-mizutani/deepalert"
+)
+
+// NotifierConfig is a declarative description of a notifier to be loaded,
+// read from environment variables or SSM parameters at deploy time.
+type NotifierConfig struct {
+	// Type selects the built-in notifier ("webhook", "slack", "sns") or,
+	// if no built-in matches, the name of an out-of-tree plugin binary
+	// under plugins/notifications/<Type>.
+	Type string `json:"type"`
+
+	// Name identifies this notifier instance in logs and errors.
+	Name string `json:"name"`
+
+	// Param carries notifier-specific configuration (URL, channel, topic ARN, ...).
+	Param map[string]string `json:"param"`
+
+	// MinSeverity filters out reports below this severity. Empty means no filter.
+	MinSeverity deepalert.Severity `json:"min_severity"`
+
+	// Attributes, when non-empty, requires at least one attribute of a
+	// matching Type (and Value, if set) to be present in the report before
+	// this notifier fires.
+	Attributes []NotifierAttributeFilter `json:"attributes"`
+}
+
+// NotifierAttributeFilter describes an deepalert.Attribute match used to
+// gate notifier dispatch.
+type NotifierAttributeFilter struct {
+	Type  deepalert.AttrType `json:"type"`
+	Value string             `json:"value,omitempty"`
+}
+
+// Notifier delivers a finalized deepalert.Report to an external system.
+// Built-in implementations live in internal/notifier; out-of-tree
+// implementations are shipped as separate binaries and loaded over
+// hashicorp/go-plugin by internal/notifier.LoadPlugin.
+type Notifier interface {
+	// Name returns the notifier instance name, used for logging.
+	Name() string
+
+	// Notify delivers report (with its resolved sections) to the
+	// destination configured for this notifier. Implementations that make
+	// outbound network calls must bound them by ctx so a hung endpoint
+	// can't block the Publisher Lambda past its execution budget.
+	Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error
+}
+
+// NotifierFactory constructs a Notifier from its declarative config.
+type NotifierFactory func(cfg NotifierConfig) (Notifier, error)
@@ -1,24 +1,53 @@
 package adaptor
 
 import (
+	"context"
 	"time"
 
-	"github.com/deepalert/deepalert/internal/models"
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/models"
 )
 
 // RepositoryFactory is interface Repository constructor
 type RepositoryFactory func(region, tableName string) Repository
 
 // Repository is interface of AWS SDK SQS
+//
+// Every method is scoped to a models.TenantID so that a single deployed
+// table can be safely shared by multiple customers/business units. In the
+// DynamoDB implementation TenantID is folded into the partition key
+// ("tenant/{id}/alertmap/...") rather than stored as its own attribute, so
+// a single-tenant deployment needs no table schema change and passes one
+// fixed TenantID (functions.DataStoreService uses "default" when it has no
+// TenantResolver).
+//
+// ctx is the first argument of every method so that callers running in a
+// Lambda with a fixed execution budget can bound (and cancel) DynamoDB
+// retries instead of risking a timeout mid-write. Implementations must
+// return ctx.Err() promptly once ctx is done.
 type Repository interface {
-	PutAlertEntry(entry *models.AlertEntry, ts time.Time) error
-	GetAlertEntry(pk, sk string) (*models.AlertEntry, error)
-	PutAlertCache(cache *models.AlertCache) error
-	GetAlertCaches(pk string) ([]*models.AlertCache, error)
-	PutReportSectionRecord(record *models.ReportSectionRecord) error
-	GetReportSection(pk string) ([]*models.ReportSectionRecord, error)
-	PutAttributeCache(attr *models.AttributeCache, ts time.Time) error
-	GetAttributeCaches(pk string) ([]*models.AttributeCache, error)
+	PutAlertEntry(ctx context.Context, tenantID models.TenantID, entry *models.AlertEntry, ts time.Time) error
+	GetAlertEntry(ctx context.Context, tenantID models.TenantID, pk, sk string) (*models.AlertEntry, error)
+	PutAlertCache(ctx context.Context, tenantID models.TenantID, cache *models.AlertCache) error
+	GetAlertCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AlertCache, error)
+	PutReportSectionRecord(ctx context.Context, tenantID models.TenantID, record *models.ReportSectionRecord) error
+	GetReportSection(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.ReportSectionRecord, error)
+	PutAttributeCache(ctx context.Context, tenantID models.TenantID, attr *models.AttributeCache, ts time.Time) error
+	GetAttributeCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AttributeCache, error)
+
+	// PutReportSummary records a models.ReportSummary when a report is
+	// published, so QueryReports/QueryAttributeUsage can answer
+	// enumeration and search questions without scanning the point-lookup
+	// keyspace above.
+	PutReportSummary(ctx context.Context, tenantID models.TenantID, summary *models.ReportSummary) error
+
+	// QueryReports enumerates recently published reports matching filter,
+	// newest first, paginating via page/the returned models.PageToken.
+	QueryReports(ctx context.Context, tenantID models.TenantID, filter models.ReportFilter, page models.PageToken) ([]models.ReportSummary, models.PageToken, error)
+
+	// QueryAttributeUsage returns every ReportID whose published report
+	// contained an attribute hashing to attrHash (deepalert.Attribute.Hash()).
+	QueryAttributeUsage(ctx context.Context, tenantID models.TenantID, attrHash string) ([]deepalert.ReportID, error)
 
 	IsConditionalCheckErr(err error) bool
 }
@@ -27,3 +56,13 @@ type Repository interface {
 func NewRepository(region, tableName string) Repository {
 	return nil
 }
+
+// TenantResolver maps an incoming deepalert.Alert to the models.TenantID
+// that owns it, e.g. by detector name, tag value or attribute content.
+// Implementations are consulted by functions.DataStoreService and
+// service.SFnService before any Repository call or state machine
+// execution so that alert data for one tenant never crosses into
+// another's keyspace or workflow.
+type TenantResolver interface {
+	Resolve(alert deepalert.Alert) (models.TenantID, error)
+}
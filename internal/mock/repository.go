@@ -1,17 +1,23 @@
 package mock
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/m-mizutani/deepalert"
 	"github.com/m-mizutani/deepalert/internal/adaptor"
 	"github.com/m-mizutani/deepalert/internal/models"
+	"github.com/m-mizutani/deepalert/internal/severity"
 )
 
 type Repository struct {
 	region    string
 	tableName string
 	data      map[string]map[string]interface{}
+	summaries map[models.TenantID][]*models.ReportSummary
 }
 
 func NewRepository(region, tableName string) adaptor.Repository {
@@ -19,11 +25,19 @@ func NewRepository(region, tableName string) adaptor.Repository {
 		region:    region,
 		tableName: tableName,
 		data:      make(map[string]map[string]interface{}),
+		summaries: make(map[models.TenantID][]*models.ReportSummary),
 	}
 }
 
 var errCondition = fmt.Errorf("condition error")
 
+// tenantKey prefixes pk with tenantID so that two tenants never share a
+// partition, mirroring the "tenant/{id}/..." scheme used by the DynamoDB
+// implementation.
+func tenantKey(tenantID models.TenantID, pk string) string {
+	return fmt.Sprintf("tenant/%s/%s", tenantID, pk)
+}
+
 func (x *Repository) put(pk, sk string, v interface{}) {
 	m, ok := x.data[pk]
 	if !ok {
@@ -56,62 +70,96 @@ func (x *Repository) getAll(pk string) []interface{} {
 	return out
 }
 
-func (x *Repository) PutAlertEntry(entry *models.AlertEntry, ts time.Time) error {
-	v := x.get(entry.PKey, entry.SKey)
+func (x *Repository) PutAlertEntry(ctx context.Context, tenantID models.TenantID, entry *models.AlertEntry, ts time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pk := tenantKey(tenantID, entry.PKey)
+	v := x.get(pk, entry.SKey)
 	if e, ok := v.(*models.AlertEntry); ok && ts.UTC().Unix() <= e.ExpiresAt {
 		return errCondition
 	}
-	x.put(entry.PKey, entry.SKey, entry)
+	x.put(pk, entry.SKey, entry)
 
 	return nil
 }
 
-func (x *Repository) GetAlertEntry(pk, sk string) (*models.AlertEntry, error) {
-	v := x.get(pk, sk)
+func (x *Repository) GetAlertEntry(ctx context.Context, tenantID models.TenantID, pk, sk string) (*models.AlertEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	v := x.get(tenantKey(tenantID, pk), sk)
 	if d, ok := v.(*models.AlertEntry); ok {
 		return d, nil
 	}
 	return nil, nil
 }
 
-func (x *Repository) PutAlertCache(cache *models.AlertCache) error {
-	x.put(cache.PKey, cache.SKey, cache)
+func (x *Repository) PutAlertCache(ctx context.Context, tenantID models.TenantID, cache *models.AlertCache) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	x.put(tenantKey(tenantID, cache.PKey), cache.SKey, cache)
 	return nil
 }
 
-func (x *Repository) GetAlertCaches(pk string) ([]*models.AlertCache, error) {
+func (x *Repository) GetAlertCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AlertCache, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var out []*models.AlertCache
-	for _, v := range x.getAll(pk) {
+	for _, v := range x.getAll(tenantKey(tenantID, pk)) {
 		out = append(out, v.(*models.AlertCache))
 	}
 	return out, nil
 }
 
-func (x *Repository) PutReportSectionRecord(record *models.ReportSectionRecord) error {
-	x.put(record.PKey, record.SKey, record)
+func (x *Repository) PutReportSectionRecord(ctx context.Context, tenantID models.TenantID, record *models.ReportSectionRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	x.put(tenantKey(tenantID, record.PKey), record.SKey, record)
 	return nil
 }
 
-func (x *Repository) GetReportSection(pk string) ([]*models.ReportSectionRecord, error) {
+func (x *Repository) GetReportSection(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.ReportSectionRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var out []*models.ReportSectionRecord
-	for _, v := range x.getAll(pk) {
+	for _, v := range x.getAll(tenantKey(tenantID, pk)) {
 		out = append(out, v.(*models.ReportSectionRecord))
 	}
 	return out, nil
 }
 
-func (x *Repository) PutAttributeCache(attr *models.AttributeCache, ts time.Time) error {
-	v := x.get(attr.PKey, attr.SKey)
+func (x *Repository) PutAttributeCache(ctx context.Context, tenantID models.TenantID, attr *models.AttributeCache, ts time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pk := tenantKey(tenantID, attr.PKey)
+	v := x.get(pk, attr.SKey)
 	if e, ok := v.(*models.AttributeCache); ok && ts.UTC().Unix() <= e.ExpiresAt {
 		return errCondition
 	}
-	x.put(attr.PKey, attr.SKey, attr)
+	x.put(pk, attr.SKey, attr)
 
 	return nil
 }
-func (x *Repository) GetAttributeCaches(pk string) ([]*models.AttributeCache, error) {
+func (x *Repository) GetAttributeCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AttributeCache, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var out []*models.AttributeCache
-	for _, v := range x.getAll(pk) {
+	for _, v := range x.getAll(tenantKey(tenantID, pk)) {
 		out = append(out, v.(*models.AttributeCache))
 	}
 	return out, nil
@@ -120,3 +168,81 @@ func (x *Repository) GetAttributeCaches(pk string) ([]*models.AttributeCache, er
 func (x *Repository) IsConditionalCheckErr(err error) bool {
 	return err == errCondition
 }
+
+func (x *Repository) PutReportSummary(ctx context.Context, tenantID models.TenantID, summary *models.ReportSummary) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	x.summaries[tenantID] = append(x.summaries[tenantID], summary)
+	return nil
+}
+
+// QueryReports does a straight in-memory scan of every summary recorded
+// for tenantID, newest first, and emulates cursor pagination by encoding
+// the offset to resume from as the page token.
+func (x *Repository) QueryReports(ctx context.Context, tenantID models.TenantID, filter models.ReportFilter, page models.PageToken) ([]models.ReportSummary, models.PageToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	all := append([]*models.ReportSummary{}, x.summaries[tenantID]...)
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+
+	var matched []models.ReportSummary
+	for _, s := range all {
+		if !severity.AtLeast(s.Severity, filter.MinSeverity) {
+			continue
+		}
+		if !filter.Since.IsZero() && s.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		matched = append(matched, *s)
+	}
+
+	offset := 0
+	if page != "" {
+		n, err := strconv.Atoi(string(page))
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page token: %s", page)
+		}
+		offset = n
+	}
+
+	const pageSize = 20
+	if offset >= len(matched) {
+		return nil, "", nil
+	}
+
+	end := offset + pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	var next models.PageToken
+	if end < len(matched) {
+		next = models.PageToken(strconv.Itoa(end))
+	}
+
+	return matched[offset:end], next, nil
+}
+
+// QueryAttributeUsage scans every summary recorded for tenantID for
+// attrHash among its recorded attribute hashes.
+func (x *Repository) QueryAttributeUsage(ctx context.Context, tenantID models.TenantID, attrHash string) ([]deepalert.ReportID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []deepalert.ReportID
+	for _, s := range x.summaries[tenantID] {
+		for _, h := range s.AttrHashes {
+			if h == attrHash {
+				out = append(out, s.ReportID)
+				break
+			}
+		}
+	}
+
+	return out, nil
+}
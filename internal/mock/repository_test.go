@@ -0,0 +1,49 @@
+package mock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/deepalert/internal/mock"
+	"github.com/m-mizutani/deepalert/internal/models"
+)
+
+// TestRepositoryTenantIsolation verifies that the tenant-prefixed keying
+// scheme shared by mock.Repository and functions.DataStoreService.TakeReport
+// actually isolates tenants: tenantB must not be able to read the
+// AlertEntry (and therefore the ReportID) that tenantA wrote under the
+// same pk/sk.
+func TestRepositoryTenantIsolation(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewRepository("dummy-region", "dummy-table")
+
+	const tenantA = models.TenantID("tenant-a")
+	const tenantB = models.TenantID("tenant-b")
+
+	entry := &models.AlertEntry{
+		PKey:     "alertmap/same-alert-id",
+		SKey:     "Fixed",
+		ReportID: "report-belonging-to-tenant-a",
+	}
+
+	if err := repo.PutAlertEntry(ctx, tenantA, entry, time.Now()); err != nil {
+		t.Fatalf("PutAlertEntry for tenantA failed: %v", err)
+	}
+
+	got, err := repo.GetAlertEntry(ctx, tenantA, entry.PKey, entry.SKey)
+	if err != nil {
+		t.Fatalf("GetAlertEntry for tenantA failed: %v", err)
+	}
+	if got == nil || got.ReportID != entry.ReportID {
+		t.Fatalf("tenantA did not get back its own entry, got=%+v", got)
+	}
+
+	leaked, err := repo.GetAlertEntry(ctx, tenantB, entry.PKey, entry.SKey)
+	if err != nil {
+		t.Fatalf("GetAlertEntry for tenantB failed: %v", err)
+	}
+	if leaked != nil {
+		t.Fatalf("tenantB read tenantA's ReportID %q via a shared pk/sk, tenant isolation is broken", leaked.ReportID)
+	}
+}
@@ -0,0 +1,124 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/severity"
+)
+
+// builtins maps a NotifierConfig.Type to its in-process constructor. Types
+// not found here are assumed to be out-of-tree plugins and are resolved by
+// LoadPlugin instead.
+var builtins = map[string]adaptor.NotifierFactory{
+	"webhook": newWebhookNotifier,
+	"slack":   newSlackNotifier,
+	"sns":     newSNSNotifier,
+}
+
+// Dispatcher fans a finalized report out to every configured Notifier whose
+// filter matches.
+type Dispatcher struct {
+	notifiers []adaptor.Notifier
+	configs   []adaptor.NotifierConfig
+	clients   []*plugin.Client
+}
+
+// NewDispatcher builds the notifier set described by configs. Built-in
+// types are constructed in-process; any other Type is loaded as an
+// out-of-tree plugin binary from pluginDir. Call Close when the
+// Dispatcher is no longer needed to stop any plugin subprocesses it
+// started.
+func NewDispatcher(configs []adaptor.NotifierConfig, pluginDir string) (*Dispatcher, error) {
+	d := &Dispatcher{}
+
+	for _, cfg := range configs {
+		factory, ok := builtins[cfg.Type]
+		if !ok {
+			notifier, client, err := LoadPlugin(pluginDir, cfg)
+			if err != nil {
+				d.Close()
+				return nil, errors.Wrap(err, "Fail to load notifier plugin").With("config", cfg)
+			}
+			d.notifiers = append(d.notifiers, notifier)
+			d.configs = append(d.configs, cfg)
+			d.clients = append(d.clients, client)
+			continue
+		}
+
+		notifier, err := factory(cfg)
+		if err != nil {
+			d.Close()
+			return nil, errors.Wrap(err, "Fail to build notifier").With("config", cfg)
+		}
+		d.notifiers = append(d.notifiers, notifier)
+		d.configs = append(d.configs, cfg)
+	}
+
+	return d, nil
+}
+
+// Close terminates every plugin subprocess this Dispatcher started. It is
+// safe to call even if some or all notifiers were built-in.
+func (d *Dispatcher) Close() {
+	for _, client := range d.clients {
+		if client != nil {
+			client.Kill()
+		}
+	}
+}
+
+// Dispatch sends report to every notifier whose config matches report's
+// severity and the resolved sections' attributes; report.Status is not
+// checked. ctx bounds each
+// notifier's delivery so a single hung endpoint can't block the Publisher
+// Lambda past its execution budget. Errors from individual notifiers are
+// collected but do not stop delivery to others.
+func (d *Dispatcher) Dispatch(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	var errs []error
+
+	for i, notifier := range d.notifiers {
+		cfg := d.configs[i]
+		if !matchConfig(cfg, report, sections) {
+			continue
+		}
+
+		if err := notifier.Notify(ctx, report, sections); err != nil {
+			errs = append(errs, errors.Wrap(err, "Fail to notify").With("notifier", notifier.Name()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("One or more notifiers failed").With("errors", errs)
+	}
+
+	return nil
+}
+
+func matchConfig(cfg adaptor.NotifierConfig, report *deepalert.Report, sections []deepalert.ReportSection) bool {
+	if !severity.AtLeast(report.Severity, cfg.MinSeverity) {
+		return false
+	}
+
+	if len(cfg.Attributes) == 0 {
+		return true
+	}
+
+	for _, filter := range cfg.Attributes {
+		for _, section := range sections {
+			for _, attr := range section.Attributes {
+				if attr.Type != filter.Type {
+					continue
+				}
+				if filter.Value == "" || attr.Value == filter.Value {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"context"
+	"net/rpc"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+)
+
+// Handshake is shared by the host and every out-of-tree notifier plugin so
+// that mismatched builds fail fast instead of producing garbage RPC calls.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "DEEPALERT_NOTIFIER_PLUGIN",
+	MagicCookieValue: "deepalert",
+}
+
+// pluginMap registers the single "notifier" plugin type that every
+// out-of-tree binary under plugins/notifications/<name> must implement.
+var pluginMap = map[string]plugin.Plugin{
+	"notifier": &notifierPlugin{},
+}
+
+// NotifyArgs is the RPC argument pair for Notifier.Notify. net/rpc only
+// registers methods whose argument types are exported.
+type NotifyArgs struct {
+	Report   *deepalert.Report
+	Sections []deepalert.ReportSection
+}
+
+// notifierPlugin adapts adaptor.Notifier to hashicorp/go-plugin's net/rpc
+// transport.
+type notifierPlugin struct {
+	Impl adaptor.Notifier
+}
+
+func (p *notifierPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return &notifierRPCServer{impl: p.Impl}, nil
+}
+
+func (p *notifierPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &notifierRPCClient{client: c}, nil
+}
+
+// Serve is called by out-of-tree plugin binaries' main() to expose impl
+// over RPC to the deepalert host process.
+func Serve(impl adaptor.Notifier) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			"notifier": &notifierPlugin{Impl: impl},
+		},
+	})
+}
+
+type notifierRPCServer struct {
+	impl adaptor.Notifier
+}
+
+func (s *notifierRPCServer) Name(_ struct{}, resp *string) error {
+	*resp = s.impl.Name()
+	return nil
+}
+
+func (s *notifierRPCServer) Notify(args NotifyArgs, _ *struct{}) error {
+	return s.impl.Notify(context.Background(), args.Report, args.Sections)
+}
+
+// notifierRPCClient implements adaptor.Notifier by forwarding calls to the
+// plugin subprocess.
+type notifierRPCClient struct {
+	client *rpc.Client
+}
+
+func (c *notifierRPCClient) Name() string {
+	var resp string
+	if err := c.client.Call("Plugin.Name", struct{}{}, &resp); err != nil {
+		return ""
+	}
+	return resp
+}
+
+// Notify forwards to the plugin subprocess over net/rpc, which has no
+// native context support: ctx only gates whether the call is issued at
+// all (an already-done ctx returns immediately without calling out), not
+// mid-call cancellation once the RPC round trip has started.
+func (c *notifierRPCClient) Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.client.Call("Plugin.Notify", NotifyArgs{Report: report, Sections: sections}, &struct{}{})
+}
+
+// LoadPlugin launches the out-of-tree notifier binary named cfg.Type under
+// dir (plugins/notifications/<cfg.Type>) and returns an adaptor.Notifier
+// backed by it, along with the *plugin.Client that owns its subprocess.
+// The caller is responsible for calling Kill() on the returned client
+// (typically via Dispatcher.Close) once the notifier is no longer needed,
+// or the plugin subprocess will outlive the host process.
+func LoadPlugin(dir string, cfg adaptor.NotifierConfig) (adaptor.Notifier, *plugin.Client, error) {
+	path := filepath.Join(dir, cfg.Type)
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         pluginMap,
+		Cmd:             exec.Command(path),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, nil, errors.Wrap(err, "Fail to start notifier plugin").With("path", path)
+	}
+
+	raw, err := rpcClient.Dispense("notifier")
+	if err != nil {
+		client.Kill()
+		return nil, nil, errors.Wrap(err, "Fail to dispense notifier plugin").With("path", path)
+	}
+
+	notifier, ok := raw.(adaptor.Notifier)
+	if !ok {
+		client.Kill()
+		return nil, nil, errors.New("notifier plugin does not implement adaptor.Notifier").With("path", path)
+	}
+
+	return notifier, client, nil
+}
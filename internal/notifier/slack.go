@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+)
+
+// slackNotifier posts a summary of the report to a Slack incoming webhook.
+type slackNotifier struct {
+	name       string
+	webhookURL string
+	channel    string
+}
+
+type slackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+func newSlackNotifier(cfg adaptor.NotifierConfig) (adaptor.Notifier, error) {
+	url, ok := cfg.Param["webhook_url"]
+	if !ok || url == "" {
+		return nil, errors.New("slack notifier requires param.webhook_url").With("config", cfg)
+	}
+
+	return &slackNotifier{
+		name:       cfg.Name,
+		webhookURL: url,
+		channel:    cfg.Param["channel"],
+	}, nil
+}
+
+func (x *slackNotifier) Name() string { return x.name }
+
+func (x *slackNotifier) Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	msg := slackMessage{
+		Channel: x.channel,
+		Text:    fmt.Sprintf("deepalert report %s published (severity=%s, status=%s)", report.ID, report.Severity, report.Status),
+	}
+
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "Fail to marshal slack message")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.webhookURL, bytes.NewReader(raw))
+	if err != nil {
+		return errors.Wrap(err, "Fail to build slack request").With("report_id", report.ID)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Fail to send slack message").With("report_id", report.ID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.New("slack webhook returned error status").With("status", resp.StatusCode)
+	}
+
+	return nil
+}
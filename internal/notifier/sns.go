@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+)
+
+// snsNotifier publishes the report to an AWS SNS topic.
+type snsNotifier struct {
+	name     string
+	topicArn string
+	svc      *sns.SNS
+}
+
+func newSNSNotifier(cfg adaptor.NotifierConfig) (adaptor.Notifier, error) {
+	topicArn, ok := cfg.Param["topic_arn"]
+	if !ok || topicArn == "" {
+		return nil, errors.New("sns notifier requires param.topic_arn").With("config", cfg)
+	}
+
+	ssn := session.Must(session.NewSession(&aws.Config{Region: aws.String(cfg.Param["region"])}))
+
+	return &snsNotifier{
+		name:     cfg.Name,
+		topicArn: topicArn,
+		svc:      sns.New(ssn),
+	}, nil
+}
+
+func (x *snsNotifier) Name() string { return x.name }
+
+func (x *snsNotifier) Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	raw, err := json.Marshal(struct {
+		Report   *deepalert.Report         `json:"report"`
+		Sections []deepalert.ReportSection `json:"sections"`
+	}{report, sections})
+	if err != nil {
+		return errors.Wrap(err, "Fail to marshal report for SNS")
+	}
+
+	input := sns.PublishInput{
+		TopicArn: aws.String(x.topicArn),
+		Message:  aws.String(string(raw)),
+	}
+
+	if _, err := x.svc.PublishWithContext(ctx, &input); err != nil {
+		return errors.Wrap(err, "Fail to publish to SNS topic").With("topic_arn", x.topicArn)
+	}
+
+	return nil
+}
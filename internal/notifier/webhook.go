@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+)
+
+// webhookNotifier delivers a report as a JSON POST to an HTTPS endpoint.
+type webhookNotifier struct {
+	name string
+	url  string
+}
+
+type webhookPayload struct {
+	Report   *deepalert.Report        `json:"report"`
+	Sections []deepalert.ReportSection `json:"sections"`
+}
+
+func newWebhookNotifier(cfg adaptor.NotifierConfig) (adaptor.Notifier, error) {
+	url, ok := cfg.Param["url"]
+	if !ok || url == "" {
+		return nil, errors.New("webhook notifier requires param.url").With("config", cfg)
+	}
+
+	return &webhookNotifier{name: cfg.Name, url: url}, nil
+}
+
+func (x *webhookNotifier) Name() string { return x.name }
+
+func (x *webhookNotifier) Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	raw, err := json.Marshal(webhookPayload{Report: report, Sections: sections})
+	if err != nil {
+		return errors.Wrap(err, "Fail to marshal webhook payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.url, bytes.NewReader(raw))
+	if err != nil {
+		return errors.Wrap(err, "Fail to build webhook request").With("url", x.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Fail to send webhook request").With("url", x.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.New("webhook endpoint returned error status").With("url", x.url).With("status", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package repository wires up the adaptor.Repository implementation
+// selected by the operator at deploy time.
+package repository
+
+import (
+	"os"
+
+	"github.com/m-mizutani/deepalert/internal/adaptor"
+	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/repository/sql"
+)
+
+// Kind identifies a Repository backend, selected via DEEPALERT_REPO_KIND.
+type Kind string
+
+const (
+	KindDynamo   Kind = "dynamo"
+	KindPostgres Kind = "postgres"
+	KindSQLite   Kind = "sqlite"
+)
+
+// New builds the adaptor.Repository backend named by kind. For
+// KindPostgres/KindSQLite, tableName is used as the DSN/connection
+// string rather than a DynamoDB table name.
+func New(kind Kind, region, tableName string) (adaptor.Repository, error) {
+	switch kind {
+	case "", KindDynamo:
+		return adaptor.NewRepository(region, tableName), nil
+	case KindPostgres:
+		return sql.Open(sql.DriverPostgres, tableName)
+	case KindSQLite:
+		return sql.Open(sql.DriverSQLite, tableName)
+	default:
+		return nil, errors.New("Unknown repository kind").With("kind", kind)
+	}
+}
+
+// NewFromEnv builds the Repository backend selected by DEEPALERT_REPO_KIND
+// (defaulting to dynamo), using region/tableName as the DynamoDB table or
+// SQL DSN depending on the selected kind.
+func NewFromEnv(region, tableName string) (adaptor.Repository, error) {
+	return New(Kind(os.Getenv("DEEPALERT_REPO_KIND")), region, tableName)
+}
@@ -0,0 +1,70 @@
+package sql
+
+// schema creates the four normalized tables backing adaptor.Repository.
+// Each mirrors a DynamoDB record kind from functions.DataStoreService's
+// key design, with (tenant_id, pk, sk) as the composite primary key so a
+// single table safely partitions multiple tenants.
+const schema = `
+CREATE TABLE IF NOT EXISTS alert_entry (
+	tenant_id  TEXT NOT NULL,
+	pk         TEXT NOT NULL,
+	sk         TEXT NOT NULL,
+	report_id  TEXT NOT NULL,
+	expires_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	PRIMARY KEY (tenant_id, pk, sk)
+);
+
+CREATE TABLE IF NOT EXISTS alert_cache (
+	tenant_id  TEXT NOT NULL,
+	pk         TEXT NOT NULL,
+	sk         TEXT NOT NULL,
+	alert_data BYTEA NOT NULL,
+	expires_at BIGINT NOT NULL,
+	PRIMARY KEY (tenant_id, pk, sk)
+);
+
+CREATE TABLE IF NOT EXISTS report_section (
+	tenant_id  TEXT NOT NULL,
+	pk         TEXT NOT NULL,
+	sk         TEXT NOT NULL,
+	data       BYTEA NOT NULL,
+	expires_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	PRIMARY KEY (tenant_id, pk, sk)
+);
+
+CREATE TABLE IF NOT EXISTS attribute_cache (
+	tenant_id  TEXT NOT NULL,
+	pk         TEXT NOT NULL,
+	sk         TEXT NOT NULL,
+	attr_key   TEXT NOT NULL,
+	attr_type  TEXT NOT NULL,
+	attr_value TEXT NOT NULL,
+	timestamp  BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	PRIMARY KEY (tenant_id, pk, sk)
+);
+
+-- report_summary is populated once per published report and backs
+-- QueryReports/QueryAttributeUsage; the index below plays the role of the
+-- DynamoDB GSI on (attr_hash, created_at) used to answer "which reports
+-- touched attribute X" without a full table scan.
+CREATE TABLE IF NOT EXISTS report_summary (
+	tenant_id  TEXT NOT NULL,
+	report_id  TEXT NOT NULL,
+	severity   TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	attr_hash  TEXT NOT NULL,
+	PRIMARY KEY (tenant_id, report_id, attr_hash)
+);
+
+CREATE INDEX IF NOT EXISTS report_summary_attr_hash_idx ON report_summary (tenant_id, attr_hash, created_at);
+`
+
+func (x *Repository) migrate() error {
+	_, err := x.db.Exec(schema)
+	return err
+}
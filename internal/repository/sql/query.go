@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/models"
+	"github.com/m-mizutani/deepalert/internal/severity"
+)
+
+const queryPageSize = 20
+
+// PutReportSummary inserts one report_summary row per attribute hash in
+// summary (or a single hash-less row if it has none), so a report
+// touching N distinct attributes is discoverable by any of them.
+func (x *Repository) PutReportSummary(ctx context.Context, tenantID models.TenantID, summary *models.ReportSummary) error {
+	hashes := summary.AttrHashes
+	if len(hashes) == 0 {
+		hashes = []string{""}
+	}
+
+	query := x.rebind("INSERT INTO report_summary (tenant_id, report_id, severity, status, created_at, attr_hash) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (tenant_id, report_id, attr_hash) DO NOTHING")
+
+	for _, hash := range hashes {
+		if _, err := x.db.ExecContext(ctx, query, tenantID, summary.ReportID, summary.Severity, summary.Status, summary.CreatedAt.Unix(), hash); err != nil {
+			return errors.Wrap(err, "Fail to put report summary").With("report_id", summary.ReportID)
+		}
+	}
+
+	return nil
+}
+
+// reportCursor is the decoded form of a QueryReports page token: the
+// (created_at, report_id) of the last row on the previous page. report_id
+// breaks ties between rows sharing a created_at second so no row is ever
+// skipped or repeated across pages.
+type reportCursor struct {
+	createdAt int64
+	reportID  string
+}
+
+func (c reportCursor) String() models.PageToken {
+	return models.PageToken(strconv.FormatInt(c.createdAt, 10) + ":" + c.reportID)
+}
+
+func parseReportCursor(page models.PageToken) (reportCursor, error) {
+	parts := strings.SplitN(string(page), ":", 2)
+	if len(parts) != 2 {
+		return reportCursor{}, errors.New("Malformed page token").With("page", page)
+	}
+
+	createdAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return reportCursor{}, errors.Wrap(err, "Invalid page token").With("page", page)
+	}
+
+	return reportCursor{createdAt: createdAt, reportID: parts[1]}, nil
+}
+
+// QueryReports enumerates distinct reports for tenantID matching filter,
+// newest first, with a cursor-style page token encoding (created_at,
+// report_id) of the last row returned so large tables can be paginated
+// safely without skipping rows that share a created_at second.
+func (x *Repository) QueryReports(ctx context.Context, tenantID models.TenantID, filter models.ReportFilter, page models.PageToken) ([]models.ReportSummary, models.PageToken, error) {
+	query := "SELECT report_id, severity, status, MAX(created_at) FROM report_summary WHERE tenant_id = ?"
+	args := []interface{}{tenantID}
+
+	if filter.MinSeverity != "" {
+		query += " AND " + severity.CaseExpr("severity") + " >= " + severity.CaseExpr("?")
+		args = append(args, filter.MinSeverity)
+	}
+
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.Since.Unix())
+	}
+
+	query += " GROUP BY report_id, severity, status"
+
+	if page != "" {
+		cursor, err := parseReportCursor(page)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " HAVING MAX(created_at) < ? OR (MAX(created_at) = ? AND report_id < ?)"
+		args = append(args, cursor.createdAt, cursor.createdAt, cursor.reportID)
+	}
+
+	query += " ORDER BY MAX(created_at) DESC, report_id DESC LIMIT ?"
+	args = append(args, queryPageSize+1)
+
+	rows, err := x.db.QueryContext(ctx, x.rebind(query), args...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Fail to query reports").With("tenant_id", tenantID)
+	}
+	defer rows.Close()
+
+	var out []models.ReportSummary
+	var cursors []reportCursor
+	for rows.Next() {
+		var s models.ReportSummary
+		var createdAt int64
+		if err := rows.Scan(&s.ReportID, &s.Severity, &s.Status, &createdAt); err != nil {
+			return nil, "", errors.Wrap(err, "Fail to scan report summary row")
+		}
+		s.CreatedAt = time.Unix(createdAt, 0).UTC()
+		cursors = append(cursors, reportCursor{createdAt: createdAt, reportID: string(s.ReportID)})
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", errors.Wrap(err, "Fail to iterate report summary rows")
+	}
+
+	var next models.PageToken
+	if len(out) > queryPageSize {
+		out = out[:queryPageSize]
+		next = cursors[queryPageSize-1].String()
+	}
+
+	return out, next, nil
+}
+
+// QueryAttributeUsage returns every distinct ReportID that touched
+// attrHash for tenantID.
+func (x *Repository) QueryAttributeUsage(ctx context.Context, tenantID models.TenantID, attrHash string) ([]deepalert.ReportID, error) {
+	query := x.rebind("SELECT DISTINCT report_id FROM report_summary WHERE tenant_id = ? AND attr_hash = ? ORDER BY report_id")
+
+	rows, err := x.db.QueryContext(ctx, query, tenantID, attrHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to query attribute usage").With("attr_hash", attrHash)
+	}
+	defer rows.Close()
+
+	var out []deepalert.ReportID
+	for rows.Next() {
+		var id deepalert.ReportID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "Fail to scan report id")
+		}
+		out = append(out, id)
+	}
+
+	return out, rows.Err()
+}
@@ -0,0 +1,114 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/models"
+)
+
+func (x *Repository) PutAlertEntry(ctx context.Context, tenantID models.TenantID, entry *models.AlertEntry, ts time.Time) error {
+	return x.conditionalUpsert(ctx, "alert_entry", tenantID, entry.PKey, entry.SKey, ts,
+		[]string{"report_id", "expires_at", "created_at"},
+		[]interface{}{entry.ReportID, entry.ExpiresAt, entry.CreatedAt})
+}
+
+func (x *Repository) GetAlertEntry(ctx context.Context, tenantID models.TenantID, pk, sk string) (*models.AlertEntry, error) {
+	query := x.rebind("SELECT report_id, expires_at, created_at FROM alert_entry WHERE tenant_id = ? AND pk = ? AND sk = ?")
+
+	var entry models.AlertEntry
+	entry.PKey, entry.SKey = pk, sk
+	err := x.db.QueryRowContext(ctx, query, tenantID, pk, sk).Scan(&entry.ReportID, &entry.ExpiresAt, &entry.CreatedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "Fail to get alert entry").With("pk", pk).With("sk", sk)
+	}
+
+	return &entry, nil
+}
+
+func (x *Repository) PutAlertCache(ctx context.Context, tenantID models.TenantID, cache *models.AlertCache) error {
+	query := x.rebind("INSERT INTO alert_cache (tenant_id, pk, sk, alert_data, expires_at) VALUES (?, ?, ?, ?, ?)")
+	if _, err := x.db.ExecContext(ctx, query, tenantID, cache.PKey, cache.SKey, cache.AlertData, cache.ExpiresAt); err != nil {
+		return errors.Wrap(err, "Fail to put alert cache").With("pk", cache.PKey)
+	}
+	return nil
+}
+
+func (x *Repository) GetAlertCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AlertCache, error) {
+	query := x.rebind("SELECT sk, alert_data, expires_at FROM alert_cache WHERE tenant_id = ? AND pk = ?")
+	rows, err := x.db.QueryContext(ctx, query, tenantID, pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to get alert caches").With("pk", pk)
+	}
+	defer rows.Close()
+
+	var out []*models.AlertCache
+	for rows.Next() {
+		cache := &models.AlertCache{PKey: pk}
+		if err := rows.Scan(&cache.SKey, &cache.AlertData, &cache.ExpiresAt); err != nil {
+			return nil, errors.Wrap(err, "Fail to scan alert cache row").With("pk", pk)
+		}
+		out = append(out, cache)
+	}
+
+	return out, rows.Err()
+}
+
+func (x *Repository) PutReportSectionRecord(ctx context.Context, tenantID models.TenantID, record *models.ReportSectionRecord) error {
+	query := x.rebind("INSERT INTO report_section (tenant_id, pk, sk, data, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)")
+	if _, err := x.db.ExecContext(ctx, query, tenantID, record.PKey, record.SKey, record.Data, record.ExpiresAt, record.CreatedAt); err != nil {
+		return errors.Wrap(err, "Fail to put report section").With("pk", record.PKey)
+	}
+	return nil
+}
+
+func (x *Repository) GetReportSection(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.ReportSectionRecord, error) {
+	query := x.rebind("SELECT sk, data, expires_at, created_at FROM report_section WHERE tenant_id = ? AND pk = ?")
+	rows, err := x.db.QueryContext(ctx, query, tenantID, pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to get report sections").With("pk", pk)
+	}
+	defer rows.Close()
+
+	var out []*models.ReportSectionRecord
+	for rows.Next() {
+		record := &models.ReportSectionRecord{PKey: pk}
+		if err := rows.Scan(&record.SKey, &record.Data, &record.ExpiresAt, &record.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "Fail to scan report section row").With("pk", pk)
+		}
+		out = append(out, record)
+	}
+
+	return out, rows.Err()
+}
+
+func (x *Repository) PutAttributeCache(ctx context.Context, tenantID models.TenantID, attr *models.AttributeCache, ts time.Time) error {
+	return x.conditionalUpsert(ctx, "attribute_cache", tenantID, attr.PKey, attr.SKey, ts,
+		[]string{"attr_key", "attr_type", "attr_value", "timestamp", "expires_at", "created_at"},
+		[]interface{}{attr.AttrKey, attr.AttrType, attr.AttrValue, attr.Timestamp, attr.ExpiresAt, attr.CreatedAt})
+}
+
+func (x *Repository) GetAttributeCaches(ctx context.Context, tenantID models.TenantID, pk string) ([]*models.AttributeCache, error) {
+	query := x.rebind("SELECT sk, attr_key, attr_type, attr_value, timestamp, expires_at, created_at FROM attribute_cache WHERE tenant_id = ? AND pk = ?")
+	rows, err := x.db.QueryContext(ctx, query, tenantID, pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to get attribute caches").With("pk", pk)
+	}
+	defer rows.Close()
+
+	var out []*models.AttributeCache
+	for rows.Next() {
+		attr := &models.AttributeCache{PKey: pk}
+		if err := rows.Scan(&attr.SKey, &attr.AttrKey, &attr.AttrType, &attr.AttrValue, &attr.Timestamp, &attr.ExpiresAt, &attr.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "Fail to scan attribute cache row").With("pk", pk)
+		}
+		out = append(out, attr)
+	}
+
+	return out, rows.Err()
+}
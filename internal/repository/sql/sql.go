@@ -0,0 +1,133 @@
+// Package sql implements adaptor.Repository against a database/sql
+// backend (PostgreSQL or SQLite), as an alternative to the DynamoDB
+// implementation for operators who don't want AWS lock-in on the state
+// store.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"  // registers the "postgres" driver
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+
+	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/models"
+)
+
+// Driver selects the SQL dialect used for conditional-put emulation and
+// migrations.
+type Driver string
+
+const (
+	// DriverPostgres targets PostgreSQL via lib/pq.
+	DriverPostgres Driver = "postgres"
+	// DriverSQLite targets SQLite, intended for local development and tests.
+	DriverSQLite Driver = "sqlite"
+)
+
+// Repository is a database/sql backed adaptor.Repository. It models the
+// four DynamoDB record kinds (alert_entry, alert_cache, report_section,
+// attribute_cache) as normalized tables keyed by (tenant_id, pk, sk),
+// matching the key scheme documented in functions.DataStoreService with
+// the tenant held in its own column.
+type Repository struct {
+	db     *sql.DB
+	driver Driver
+}
+
+// Open connects to the SQL backend identified by driver/dsn and ensures
+// its schema is migrated.
+func Open(driver Driver, dsn string) (*Repository, error) {
+	db, err := sql.Open(string(driver), dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fail to open SQL database").With("driver", driver)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, errors.Wrap(err, "Fail to ping SQL database").With("driver", driver)
+	}
+
+	repo := &Repository{db: db, driver: driver}
+	if err := repo.migrate(); err != nil {
+		return nil, errors.Wrap(err, "Fail to migrate SQL schema")
+	}
+
+	return repo, nil
+}
+
+// conditionalCheckErr mirrors the DynamoDB ConditionalCheckFailedException:
+// it's returned in place of a DB error when a conditional INSERT's ON
+// CONFLICT predicate rejects the row rather than when the query itself
+// fails.
+type conditionalCheckErr struct{}
+
+func (conditionalCheckErr) Error() string { return "conditional check failed" }
+
+var errConditionalCheck error = conditionalCheckErr{}
+
+func (x *Repository) IsConditionalCheckErr(err error) bool {
+	_, ok := err.(conditionalCheckErr)
+	return ok
+}
+
+// conditionalUpsert performs INSERT ... ON CONFLICT (tenant_id, pk, sk) DO UPDATE,
+// but only when the existing row's expires_at has already passed,
+// emulating the DynamoDB condition
+// "(attribute_not_exists(pk) AND attribute_not_exists(sk)) OR expires_at < ?".
+func (x *Repository) conditionalUpsert(ctx context.Context, table string, tenantID models.TenantID, pk, sk string, ts time.Time, cols []string, vals []interface{}) error {
+	placeholders := make([]string, len(cols))
+	for i := range cols {
+		placeholders[i] = "?"
+	}
+
+	query := "INSERT INTO " + table + " (tenant_id, pk, sk, " + strings.Join(cols, ", ") + ") VALUES (?, ?, ?, " +
+		strings.Join(placeholders, ", ") + ") ON CONFLICT (tenant_id, pk, sk) DO UPDATE SET " +
+		setClause(cols) + " WHERE " + table + ".expires_at < ?"
+
+	args := append([]interface{}{tenantID, pk, sk}, vals...)
+	args = append(args, ts.UTC().Unix())
+
+	res, err := x.db.ExecContext(ctx, x.rebind(query), args...)
+	if err != nil {
+		return errors.Wrap(err, "Fail to upsert row").With("table", table)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errConditionalCheck
+	}
+
+	return nil
+}
+
+func setClause(cols []string) string {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = c + " = EXCLUDED." + c
+	}
+	return strings.Join(parts, ", ")
+}
+
+// rebind rewrites "?" placeholders to "$1", "$2", ... for PostgreSQL,
+// which doesn't understand the driver-agnostic "?" syntax.
+func (x *Repository) rebind(query string) string {
+	if x.driver != DriverPostgres {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$")
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
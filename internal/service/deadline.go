@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/deepalert/internal/errors"
+)
+
+// DefaultSafetyMargin is the safety margin functions.DataStoreService,
+// functions.QueryAPI and functions.PublisherService derive their working
+// context with via WithSafetyMargin, leaving enough of the Lambda's
+// execution budget to return a clean error instead of being killed
+// mid-request.
+const DefaultSafetyMargin = 5 * time.Second
+
+// WithSafetyMargin derives a child context that is cancelled margin
+// before the Lambda invocation's actual deadline, so Repository calls and
+// state machine executions bail out cleanly instead of being killed
+// mid-write when the function runs out of execution budget. The Lambda
+// runtime sets the invocation deadline on ctx itself before the handler
+// is called, so it's read via the standard ctx.Deadline().
+func WithSafetyMargin(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc, *errors.Error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, nil, errors.New("No Lambda deadline available in context")
+	}
+
+	child, cancel := context.WithDeadline(ctx, deadline.Add(-margin))
+	return child, cancel, nil
+}
+
+// WithDefaultSafetyMargin wraps WithSafetyMargin with DefaultSafetyMargin,
+// falling back to ctx unmodified (with a no-op cancel) when ctx carries no
+// deadline at all, e.g. outside a Lambda invocation or in tests. Callers
+// should always defer the returned cancel.
+func WithDefaultSafetyMargin(ctx context.Context) (context.Context, context.CancelFunc) {
+	child, cancel, err := WithSafetyMargin(ctx, DefaultSafetyMargin)
+	if err != nil {
+		return ctx, func() {}
+	}
+	return child, cancel
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -9,22 +10,37 @@ import (
 	"github.com/aws/aws-sdk-go/service/sfn"
 	"github.com/m-mizutani/deepalert/internal/adaptor"
 	"github.com/m-mizutani/deepalert/internal/errors"
+	"github.com/m-mizutani/deepalert/internal/models"
 )
 
 // SFnService is utility to use AWS StepFunctions
 type SFnService struct {
-	newSFn adaptor.SFnClientFactory
+	newSFn           adaptor.SFnClientFactory
+	stateMachineArns map[models.TenantID]string
 }
 
-// NewSFnService is constructor of SFnService
-func NewSFnService(newSFn adaptor.SFnClientFactory) *SFnService {
+// NewSFnService is constructor of SFnService. stateMachineArns maps a
+// tenant to the state machine it should be routed to; a deployment with a
+// single tenant can pass a map with one entry, keyed by the TenantID its
+// alerts resolve to ("default" in functions.DataStoreService when no
+// TenantResolver is configured).
+func NewSFnService(newSFn adaptor.SFnClientFactory, stateMachineArns map[models.TenantID]string) *SFnService {
 	return &SFnService{
-		newSFn: newSFn,
+		newSFn:           newSFn,
+		stateMachineArns: stateMachineArns,
 	}
 }
 
-// Exec invokes sfn.StartExecution with data
-func (x *SFnService) Exec(arn string, data interface{}) *errors.Error {
+// Exec invokes sfn.StartExecution with data against the state machine
+// assigned to tenantID. ctx bounds how long the AWS SDK may spend
+// (including retries) before giving up; callers on a Lambda execution
+// budget should pass a context derived via WithSafetyMargin.
+func (x *SFnService) Exec(ctx context.Context, tenantID models.TenantID, data interface{}) *errors.Error {
+	arn, ok := x.stateMachineArns[tenantID]
+	if !ok {
+		return errors.New("No state machine ARN configured for tenant").With("tenant_id", tenantID)
+	}
+
 	raw, err := json.Marshal(data)
 	if err != nil {
 		return errors.Wrap(err, "Fail to marshal report data")
@@ -45,7 +61,10 @@ func (x *SFnService) Exec(arn string, data interface{}) *errors.Error {
 		StateMachineArn: aws.String(arn),
 	}
 
-	if _, err := svc.StartExecution(&input); err != nil {
+	if _, err := svc.StartExecutionWithContext(ctx, &input); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrap(ctx.Err(), "Deadline exceeded while executing state machine").With("arn", arn)
+		}
 		return errors.Wrap(err, "Fail to execute state machine").With("arn", arn).With("data", string(raw))
 	}
 
@@ -0,0 +1,59 @@
+// Package severity provides a single, verified ordering for
+// deepalert.Severity so that notifier gating and report filtering don't
+// each independently assume the underlying string constants happen to
+// sort alphabetically in severity order.
+package severity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m-mizutani/deepalert"
+)
+
+// order is deepalert's canonical severity scale, lowest first. Every
+// caller comparing two Severity values should go through Rank/AtLeast
+// instead of Go's built-in "<" on the raw string, which only works by
+// coincidence.
+var order = []deepalert.Severity{"safe", "unclassified", "urgent", "critical"}
+
+var rank = func() map[deepalert.Severity]int {
+	m := make(map[deepalert.Severity]int, len(order))
+	for i, s := range order {
+		m[s] = i
+	}
+	return m
+}()
+
+// Rank returns s's position on the severity scale, or -1 if s isn't one
+// of the known levels.
+func Rank(s deepalert.Severity) int {
+	if r, ok := rank[s]; ok {
+		return r
+	}
+	return -1
+}
+
+// AtLeast reports whether s is ranked at or above min. An empty min
+// means "no filter" and always matches.
+func AtLeast(s, min deepalert.Severity) bool {
+	if min == "" {
+		return true
+	}
+	return Rank(s) >= Rank(min)
+}
+
+// CaseExpr renders a portable SQL "CASE column WHEN ... END" expression
+// mapping column's severity strings to the same ranks as Rank, so SQL
+// callers filter/sort by the identical ordering Go code uses instead of
+// maintaining their own copy of it.
+func CaseExpr(column string) string {
+	var sb strings.Builder
+	sb.WriteString("CASE ")
+	sb.WriteString(column)
+	for _, s := range order {
+		fmt.Fprintf(&sb, " WHEN '%s' THEN %d", s, rank[s])
+	}
+	sb.WriteString(" ELSE -1 END")
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+// Command example is a minimal out-of-tree deepalert notifier plugin. It
+// demonstrates the shape expected under plugins/notifications/<name>: a
+// standalone binary that implements adaptor.Notifier and serves it over
+// hashicorp/go-plugin RPC via notifier.Serve.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/m-mizutani/deepalert"
+	"github.com/m-mizutani/deepalert/internal/notifier"
+)
+
+type examplePlugin struct{}
+
+func (examplePlugin) Name() string { return "example" }
+
+func (examplePlugin) Notify(ctx context.Context, report *deepalert.Report, sections []deepalert.ReportSection) error {
+	log.Printf("example notifier: report %s published with %d section(s)", report.ID, len(sections))
+	return nil
+}
+
+func main() {
+	notifier.Serve(examplePlugin{})
+}